@@ -0,0 +1,137 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package casefold parses CaseFolding.txt and SpecialCasing.txt, the
+// UCD tables of code-point mappings too rich (multi-character, or
+// conditional) to fit in the 1:1 fields of UnicodeData.txt. See
+// <https://www.unicode.org/reports/tr44/#CaseFolding.txt> and
+// <https://www.unicode.org/reports/tr44/#SpecialCasing.txt>.
+package casefold
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Set holds the code-point mappings of CaseFolding.txt, keyed by
+// status class:
+//
+//	C: common, single-character mappings shared by simple and full folding
+//	F: full, multi-character mappings that differ from the simple ones
+//	S: simple, single-character mappings that differ from the full ones
+//	T: Turkic-only special cases, excluded from default folding
+type Set struct {
+	C, F, S, T map[rune][]rune
+}
+
+// Fold returns the default full case fold of r: its F mapping if
+// present, else its C mapping, else r unchanged. S and T are not
+// used by default folding; S duplicates F.exceptions for languages
+// that fold simply, and T only applies under a Turkic locale.
+func (s Set) Fold(r rune) []rune {
+	if m, ok := s.F[r]; ok {
+		return m
+	}
+	if m, ok := s.C[r]; ok {
+		return m
+	}
+	return []rune{r}
+}
+
+// ParseCaseFolding parses the contents of CaseFolding.txt. Each
+// non-blank, non-comment line has the form
+//
+//	0130; F; 0069 0307; # LATIN CAPITAL LETTER I WITH DOT ABOVE
+func ParseCaseFolding(text string) Set {
+	set := Set{
+		C: make(map[rune][]rune),
+		F: make(map[rune][]rune),
+		S: make(map[rune][]rune),
+		T: make(map[rune][]rune),
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 3 {
+			continue
+		}
+		r := parseRune(strings.TrimSpace(fields[0]))
+		mapping := parseRunes(strings.TrimSpace(fields[2]))
+		switch strings.TrimSpace(fields[1]) {
+		case "C":
+			set.C[r] = mapping
+		case "F":
+			set.F[r] = mapping
+		case "S":
+			set.S[r] = mapping
+		case "T":
+			set.T[r] = mapping
+		}
+	}
+	return set
+}
+
+// SpecialCase holds the unconditional full lower-, title-, and
+// upper-case mappings for one code point from SpecialCasing.txt.
+type SpecialCase struct {
+	Lower, Title, Upper []rune
+}
+
+// ParseSpecialCasing parses the contents of SpecialCasing.txt,
+// keeping only unconditional mappings (those with no Language or
+// Context in the fifth field). Each such line has the form
+//
+//	00DF; 0073 0073; 0053 0073; 0053 0053; # LATIN SMALL LETTER SHARP S
+func ParseSpecialCasing(text string) map[rune]SpecialCase {
+	set := make(map[rune]SpecialCase)
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 4 {
+			continue
+		}
+		if strings.TrimSpace(strings.Join(fields[4:], ";")) != "" {
+			// Conditional mapping; skip.
+			continue
+		}
+		r := parseRune(strings.TrimSpace(fields[0]))
+		set[r] = SpecialCase{
+			Lower: parseRunes(strings.TrimSpace(fields[1])),
+			Title: parseRunes(strings.TrimSpace(fields[2])),
+			Upper: parseRunes(strings.TrimSpace(fields[3])),
+		}
+	}
+	return set
+}
+
+func parseRune(s string) rune {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		panic("casefold: malformed code point " + s + ": " + err.Error())
+	}
+	return rune(v)
+}
+
+// parseRunes parses a space-separated list of code points, such as
+// "0073 0073", into the runes they represent.
+func parseRunes(s string) []rune {
+	fields := strings.Fields(s)
+	runes := make([]rune, len(fields))
+	for i, f := range fields {
+		runes[i] = parseRune(f)
+	}
+	return runes
+}
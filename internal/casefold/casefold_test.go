@@ -0,0 +1,60 @@
+package casefold
+
+import (
+	"slices"
+	"testing"
+)
+
+const sampleCaseFolding = `
+# Comments and blank lines are ignored.
+
+0041; C; 0061; # LATIN CAPITAL LETTER A
+00DF; F; 0073 0073; # LATIN SMALL LETTER SHARP S
+1E9E; F; 0073 0073; # LATIN CAPITAL LETTER SHARP S
+0130; F; 0069 0307; # LATIN CAPITAL LETTER I WITH DOT ABOVE
+0130; T; 0069; # LATIN CAPITAL LETTER I WITH DOT ABOVE
+`
+
+func TestParseCaseFolding(t *testing.T) {
+	set := ParseCaseFolding(sampleCaseFolding)
+
+	if got, want := set.Fold('A'), []rune{'a'}; !slices.Equal(got, want) {
+		t.Errorf("Fold('A') = %q, want %q", got, want)
+	}
+	if got, want := set.Fold(0x00DF), []rune{'s', 's'}; !slices.Equal(got, want) {
+		t.Errorf("Fold(0x00DF) = %q, want %q", got, want)
+	}
+	if got, want := set.Fold(0x1E9E), []rune{'s', 's'}; !slices.Equal(got, want) {
+		t.Errorf("Fold(0x1E9E) = %q, want %q", got, want)
+	}
+	if got, want := set.Fold('Z'), []rune{'Z'}; !slices.Equal(got, want) {
+		t.Errorf("Fold('Z') (unmapped) = %q, want %q", got, want)
+	}
+	if got, want := set.T[0x0130], []rune{'i'}; !slices.Equal(got, want) {
+		t.Errorf("T[0x0130] = %q, want %q", got, want)
+	}
+}
+
+const sampleSpecialCasing = `
+# Comments and blank lines are ignored.
+
+00DF; 0073 0073; 0053 0073; 0053 0053; # LATIN SMALL LETTER SHARP S
+0130; 0069 0307; 0130; 0130; # LATIN CAPITAL LETTER I WITH DOT ABOVE
+0049; 0131; 0049; 0049; tr; # LATIN CAPITAL LETTER I, conditional on Turkish
+`
+
+func TestParseSpecialCasingSkipsConditional(t *testing.T) {
+	set := ParseSpecialCasing(sampleSpecialCasing)
+
+	sc, ok := set[0x00DF]
+	if !ok {
+		t.Fatal("0x00DF not found")
+	}
+	if got, want := sc.Upper, []rune{'S', 'S'}; !slices.Equal(got, want) {
+		t.Errorf("Upper = %q, want %q", got, want)
+	}
+
+	if _, ok := set[0x0049]; ok {
+		t.Errorf("conditional entry for 0x0049 was not skipped")
+	}
+}
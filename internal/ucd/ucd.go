@@ -0,0 +1,92 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ucd assembles a version of the Unicode Character Database
+// from its component files (UnicodeData.txt, Scripts.txt, and so on)
+// into a single queryable Version, so that callers can hold several
+// versions side by side and pick among them at run time.
+package ucd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"robpike.io/cmd/unicode/internal/casefold"
+	"robpike.io/cmd/unicode/internal/unicodedb"
+	"robpike.io/cmd/unicode/internal/unicodeprop"
+)
+
+// delim separates fields within a line of UnicodeData.txt.
+const delim = ";"
+
+// Files bundles the raw contents of the UCD files that make up one
+// Version, exactly as downloaded from unicode.org.
+type Files struct {
+	UnicodeData            string
+	Scripts                string
+	Blocks                 string
+	DerivedGeneralCategory string
+	CaseFolding            string
+	SpecialCasing          string
+}
+
+// Version holds one version of the Unicode Character Database,
+// parsed from a Files bundle.
+type Version struct {
+	// Name is the Unicode version number, e.g. "15.1", or "latest".
+	Name string
+
+	// Lines holds UnicodeData.txt, one entry per line. Each line
+	// contains 15 fields separated by delim. See
+	// <https://www.unicode.org/reports/tr44/#UnicodeData.txt> for
+	// field definitions.
+	Lines []string
+
+	// DB indexes Lines for O(log n) point lookup and literal -g
+	// search, built once here rather than rescanned per query.
+	DB *unicodedb.DB
+
+	Scripts    unicodeprop.Set
+	Blocks     unicodeprop.Set
+	Categories unicodeprop.Set
+
+	CaseFolding   casefold.Set
+	SpecialCasing map[rune]casefold.SpecialCase
+}
+
+// New assembles a Version named name from f.
+func New(name string, f Files) (*Version, error) {
+	lines, err := splitLines(f.UnicodeData)
+	if err != nil {
+		return nil, err
+	}
+	return &Version{
+		Name:          name,
+		Lines:         lines,
+		DB:            unicodedb.Build(lines),
+		Scripts:       unicodeprop.Parse(f.Scripts),
+		Blocks:        unicodeprop.Parse(f.Blocks),
+		Categories:    unicodeprop.Parse(f.DerivedGeneralCategory),
+		CaseFolding:   casefold.ParseCaseFolding(f.CaseFolding),
+		SpecialCasing: casefold.ParseSpecialCasing(f.SpecialCasing),
+	}, nil
+}
+
+// splitLines splits the contents of UnicodeData.txt into lines,
+// dropping blank lines and rejecting any line that lacks the
+// field delimiter.
+func splitLines(text string) ([]string, error) {
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if len(lines[i]) == 0 {
+			lines = slices.Delete(lines, i, i+1)
+			continue
+		}
+		if !strings.Contains(lines[i], delim) {
+			return nil, fmt.Errorf("malformed database: line %d", i+1)
+		}
+	}
+	return lines, nil
+}
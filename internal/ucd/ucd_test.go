@@ -0,0 +1,40 @@
+package ucd
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	v, err := New("test", Files{
+		UnicodeData: "0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n" +
+			"0061;LATIN SMALL LETTER A;Ll;0;L;;;;;N;;;0041;;0041\n",
+		Scripts:                "0041..005A ; Latin # [26] LATIN CAPITAL LETTER A..LATIN CAPITAL LETTER Z\n",
+		Blocks:                 "0000..007F ; Basic Latin\n",
+		DerivedGeneralCategory: "0041 ; Lu # LATIN CAPITAL LETTER A\n",
+		CaseFolding:            "0041; C; 0061; # LATIN CAPITAL LETTER A\n",
+		SpecialCasing:          "00DF; 0073 0073; 0053 0073; 0053 0053; # LATIN SMALL LETTER SHARP S\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "test" {
+		t.Errorf("Name = %q, want %q", v.Name, "test")
+	}
+	if len(v.Lines) != 2 {
+		t.Fatalf("len(Lines) = %d, want 2", len(v.Lines))
+	}
+	if _, ok := v.Scripts["Latin"]; !ok {
+		t.Error(`Scripts["Latin"] not found`)
+	}
+	if _, ok := v.DB.Lookup('A'); !ok {
+		t.Error("DB.Lookup('A') not found")
+	}
+	if got := v.CaseFolding.Fold('A'); string(got) != "a" {
+		t.Errorf("CaseFolding.Fold('A') = %q, want %q", got, "a")
+	}
+}
+
+func TestNewMalformedLine(t *testing.T) {
+	_, err := New("test", Files{UnicodeData: "not a valid line\n"})
+	if err == nil {
+		t.Fatal("New with a malformed line: got nil error, want non-nil")
+	}
+}
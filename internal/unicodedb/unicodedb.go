@@ -0,0 +1,191 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package unicodedb indexes the lines of UnicodeData.txt once, at
+// construction time, so that repeated point lookups and name
+// searches don't each have to rescan the ~34k-line database.
+package unicodedb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const delim = ";"
+
+// Entry is one parsed, indexed line of UnicodeData.txt.
+type Entry struct {
+	Rune rune
+
+	// Rest is every field after the code point, still joined by
+	// delim, exactly as it appeared in the source line (or, for a
+	// rune synthesized from a First/Last range, as synthesized).
+	Rest string
+
+	// Search is the lowercased "name;u1name" string matched against
+	// a -g regexp.
+	Search string
+}
+
+// rangeEntry covers an unassigned run of code points spanned by a
+// "<Name, First>" / "<Name, Last>" pair, such as the CJK Ideograph
+// block. Names for runes in the range are synthesized on demand.
+type rangeEntry struct {
+	lo, hi rune
+	name   string // e.g. "CJK Ideograph", to become "<CJK Ideograph-4E01>"
+	rest   string // the First row's fields after the code point; all but Name are reused verbatim
+}
+
+// DB is an indexed, queryable UnicodeData.txt.
+type DB struct {
+	entries []Entry      // sorted by Rune; O(log n) point lookup, O(1) iteration
+	ranges  []rangeEntry // sorted by lo; covers First/Last rows
+
+	// trigrams maps each 3-byte substring of a lowercased Search
+	// string to the entries indices that contain it, letting a
+	// literal -g query prefilter candidates before confirming with
+	// strings.Contains.
+	trigrams map[string][]int32
+}
+
+// Build parses lines, the contents of UnicodeData.txt split into one
+// string per line, into a DB.
+func Build(lines []string) *DB {
+	db := &DB{trigrams: make(map[string][]int32)}
+
+	var firstName, firstRest string
+	var firstRune rune
+	havingFirst := false
+
+	for _, l := range lines {
+		i := strings.IndexByte(l, ';')
+		r := parseRune(l[:i])
+		rest := l[i+1:]
+		fields := strings.SplitN(rest, delim, 3)
+		name := fields[0]
+
+		switch {
+		case strings.HasSuffix(name, ", First>"):
+			firstRune, firstName, firstRest = r, strings.TrimSuffix(strings.TrimPrefix(name, "<"), ", First>"), rest
+			havingFirst = true
+			continue
+		case strings.HasSuffix(name, ", Last>"):
+			if havingFirst {
+				db.ranges = append(db.ranges, rangeEntry{lo: firstRune, hi: r, name: firstName, rest: firstRest})
+				havingFirst = false
+			}
+			continue
+		}
+
+		lower := strings.ToLower(rest)
+		lowerFields := strings.Split(lower, delim)
+		search := lowerFields[0] + delim
+		if len(lowerFields) > 9 {
+			search += lowerFields[9]
+		}
+		db.entries = append(db.entries, Entry{Rune: r, Rest: rest, Search: search})
+	}
+
+	sort.Slice(db.entries, func(i, j int) bool { return db.entries[i].Rune < db.entries[j].Rune })
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].lo < db.ranges[j].lo })
+
+	// Index trigrams only after entries reach their final, sorted
+	// positions, since the index stores positional indices into
+	// db.entries.
+	for idx, e := range db.entries {
+		seen := make(map[string]bool)
+		for i := 0; i+3 <= len(e.Search); i++ {
+			g := e.Search[i : i+3]
+			if seen[g] {
+				continue
+			}
+			seen[g] = true
+			db.trigrams[g] = append(db.trigrams[g], int32(idx))
+		}
+	}
+	return db
+}
+
+// Entries returns every entry, sorted by Rune, for O(1) iteration.
+func (db *DB) Entries() []Entry {
+	return db.entries
+}
+
+// Lookup returns the Entry for r: a directly indexed code point, or
+// (for an unassigned code point inside a First/Last range such as
+// the CJK Ideograph block) a synthesized one.
+func (db *DB) Lookup(r rune) (Entry, bool) {
+	i := sort.Search(len(db.entries), func(i int) bool { return db.entries[i].Rune >= r })
+	if i < len(db.entries) && db.entries[i].Rune == r {
+		return db.entries[i], true
+	}
+	j := sort.Search(len(db.ranges), func(j int) bool { return db.ranges[j].hi >= r })
+	if j < len(db.ranges) && db.ranges[j].lo <= r && r <= db.ranges[j].hi {
+		rg := db.ranges[j]
+		name := strconv.FormatInt(int64(r), 16)
+		return Entry{
+			Rune: r,
+			Rest: "<" + rg.name + "-" + strings.ToUpper(name) + ">" + delim + rg.rest[strings.IndexByte(rg.rest, ';')+1:],
+		}, true
+	}
+	return Entry{}, false
+}
+
+// CandidateIndices returns the indices of entries whose Search string
+// might contain lit, using the trigram index to prune. It is a
+// superset of the true matches; the caller must still confirm with
+// strings.Contains. A nil, non-empty-lit result means the trigram
+// index couldn't narrow the search (lit shorter than 3 bytes) and
+// the caller should fall back to scanning every entry.
+func (db *DB) CandidateIndices(lit string) []int32 {
+	lit = strings.ToLower(lit)
+	if len(lit) < 3 {
+		return nil
+	}
+	var candidates []int32
+	for i := 0; i+3 <= len(lit); i++ {
+		ids, ok := db.trigrams[lit[i:i+3]]
+		if !ok {
+			return []int32{}
+		}
+		if candidates == nil {
+			candidates = ids
+			continue
+		}
+		candidates = intersectSorted(candidates, ids)
+		if len(candidates) == 0 {
+			return []int32{}
+		}
+	}
+	return candidates
+}
+
+// intersectSorted returns the sorted intersection of two sorted,
+// ascending slices of entry indices.
+func intersectSorted(a, b []int32) []int32 {
+	var out []int32
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func parseRune(s string) rune {
+	v, err := strconv.ParseInt(s, 16, 32)
+	if err != nil {
+		panic("unicodedb: malformed code point " + s + ": " + err.Error())
+	}
+	return rune(v)
+}
@@ -0,0 +1,110 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unicodedb
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const sampleData = "0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n" +
+	"0061;LATIN SMALL LETTER A;Ll;0;L;;;;;N;;;0041;;0041\n" +
+	"4E00;<CJK Ideograph, First>;Lo;0;L;;;;;N;;;;;\n" +
+	"9FFF;<CJK Ideograph, Last>;Lo;0;L;;;;;N;;;;;\n"
+
+func lines(text string) []string {
+	return strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+func TestLookupEntry(t *testing.T) {
+	db := Build(lines(sampleData))
+	e, ok := db.Lookup('A')
+	if !ok {
+		t.Fatal("Lookup('A') not found")
+	}
+	if !strings.HasPrefix(e.Rest, "LATIN CAPITAL LETTER A;") {
+		t.Errorf("Lookup('A').Rest = %q, want prefix %q", e.Rest, "LATIN CAPITAL LETTER A;")
+	}
+}
+
+func TestLookupSynthesizesRangeName(t *testing.T) {
+	db := Build(lines(sampleData))
+	e, ok := db.Lookup(0x4E01)
+	if !ok {
+		t.Fatal("Lookup(0x4E01) not found")
+	}
+	if want := "<CJK Ideograph-4E01>;"; !strings.HasPrefix(e.Rest, want) {
+		t.Errorf("Lookup(0x4E01).Rest = %q, want prefix %q", e.Rest, want)
+	}
+	if _, ok := db.Lookup(0xA000); ok {
+		t.Error("Lookup(0xA000): got found, want not found")
+	}
+}
+
+func TestCandidateIndices(t *testing.T) {
+	db := Build(lines(sampleData))
+	idx := db.CandidateIndices("CAPITAL")
+	if len(idx) == 0 {
+		t.Fatal("CandidateIndices(\"CAPITAL\") returned no candidates")
+	}
+	found := false
+	for _, i := range idx {
+		if strings.Contains(db.Entries()[i].Search, "capital") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("CandidateIndices(\"CAPITAL\") didn't include the LATIN CAPITAL LETTER A entry")
+	}
+	if got := db.CandidateIndices("zzz"); len(got) != 0 {
+		t.Errorf("CandidateIndices(\"zzz\") = %v, want empty", got)
+	}
+	if got := db.CandidateIndices("ab"); got != nil {
+		t.Errorf("CandidateIndices(\"ab\") = %v, want nil (too short to index)", got)
+	}
+}
+
+func buildBenchDB(n int) *DB {
+	var ls []string
+	for i := 0; i < n; i++ {
+		ls = append(ls, "4E00;CJK UNIFIED IDEOGRAPH-4E00;Lo;0;L;;;;;N;;;;;")
+	}
+	ls = append(ls, sampleData)
+	return Build(append(ls, lines(sampleData)...))
+}
+
+// BenchmarkScanLinear mimics the pre-unicodedb approach of scanning
+// every line with a regexp match on each -g query.
+func BenchmarkScanLinear(b *testing.B) {
+	db := buildBenchDB(2000)
+	entries := db.Entries()
+	re := regexp.MustCompile("capital")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for _, e := range entries {
+			if re.MatchString(e.Search) {
+				n++
+			}
+		}
+	}
+}
+
+// BenchmarkCandidateIndices shows the speedup the trigram index gives
+// a literal-complete -g pattern over the linear scan above.
+func BenchmarkCandidateIndices(b *testing.B) {
+	db := buildBenchDB(2000)
+	entries := db.Entries()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for _, idx := range db.CandidateIndices("CAPITAL") {
+			if strings.Contains(entries[idx].Search, "capital") {
+				n++
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package unicodeprop parses UCD range files, such as Scripts.txt,
+// Blocks.txt, and DerivedGeneralCategory.txt, into *unicode.RangeTable
+// values that support O(log n) membership tests via unicode.Is.
+package unicodeprop
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Table is a named set of code points, such as the script
+// "Cyrillic", the block "Mathematical_Operators", or the general
+// category "Lu".
+type Table struct {
+	Name   string
+	Ranges *unicode.RangeTable
+}
+
+// Set maps property value names to their Tables, as found in a
+// single UCD range file.
+type Set map[string]*Table
+
+// Parse parses the contents of a UCD range file into a Set keyed by
+// property value name. Each non-blank, non-comment line has the form
+//
+//	0400..04FF    ; Cyrillic # [384] CYRILLIC CAPITAL LETTER IE WITH GRAVE..CYRILLIC SMALL LETTER
+//
+// or, for a single code point,
+//
+//	00AA          ; Latin # Lo       FEMININE ORDINAL INDICATOR
+//
+// Text from '#' to the end of the line, and blank lines, are ignored.
+func Parse(text string) Set {
+	builders := make(map[string]*builder)
+	var order []string
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ";", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		lo, hi := parseRange(strings.TrimSpace(fields[0]))
+		name := strings.TrimSpace(fields[1])
+		b, ok := builders[name]
+		if !ok {
+			b = &builder{}
+			builders[name] = b
+			order = append(order, name)
+		}
+		b.add(lo, hi)
+	}
+	set := make(Set, len(order))
+	for _, name := range order {
+		set[name] = &Table{Name: name, Ranges: builders[name].rangeTable()}
+	}
+	return set
+}
+
+// parseRange parses "XXXX" or "XXXX..YYYY" into lo and hi, inclusive.
+func parseRange(s string) (lo, hi rune) {
+	if i := strings.Index(s, ".."); i >= 0 {
+		return parseRune(s[:i]), parseRune(s[i+2:])
+	}
+	lo = parseRune(s)
+	return lo, lo
+}
+
+func parseRune(s string) rune {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		panic("unicodeprop: malformed code point " + s + ": " + err.Error())
+	}
+	return rune(v)
+}
+
+// builder accumulates ranges for a single property value and
+// produces a sorted, disjoint *unicode.RangeTable.
+type builder struct {
+	r16 []unicode.Range16
+	r32 []unicode.Range32
+}
+
+// add records the inclusive range [lo, hi], splitting it across the
+// BMP boundary if necessary.
+func (b *builder) add(lo, hi rune) {
+	switch {
+	case hi <= 0xFFFF:
+		b.r16 = append(b.r16, unicode.Range16{Lo: uint16(lo), Hi: uint16(hi), Stride: 1})
+	case lo > 0xFFFF:
+		b.r32 = append(b.r32, unicode.Range32{Lo: uint32(lo), Hi: uint32(hi), Stride: 1})
+	default:
+		b.r16 = append(b.r16, unicode.Range16{Lo: uint16(lo), Hi: 0xFFFF, Stride: 1})
+		b.r32 = append(b.r32, unicode.Range32{Lo: 0x10000, Hi: uint32(hi), Stride: 1})
+	}
+}
+
+func (b *builder) rangeTable() *unicode.RangeTable {
+	slices.SortFunc(b.r16, func(a, c unicode.Range16) int { return int(a.Lo) - int(c.Lo) })
+	slices.SortFunc(b.r32, func(a, c unicode.Range32) int { return int(a.Lo) - int(c.Lo) })
+	return &unicode.RangeTable{R16: b.r16, R32: b.r32}
+}
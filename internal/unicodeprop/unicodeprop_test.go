@@ -0,0 +1,56 @@
+package unicodeprop
+
+import (
+	"testing"
+	"unicode"
+)
+
+const sampleScripts = `
+# Comments and blank lines are ignored.
+
+0041..005A   ; Latin # L&  [26] LATIN CAPITAL LETTER A..LATIN CAPITAL LETTER Z
+0061..007A   ; Latin # L&  [26] LATIN SMALL LETTER A..LATIN SMALL LETTER Z
+00AA         ; Latin # Lo       FEMININE ORDINAL INDICATOR
+
+0000..0040   ; Common # 65 characters
+1F600..1F64F ; Common # [80] EMOJI characters, wholly above the BMP
+`
+
+func TestParseMultiRangeScript(t *testing.T) {
+	set := Parse(sampleScripts)
+	latin, ok := set["Latin"]
+	if !ok {
+		t.Fatal(`"Latin" not found in parsed set`)
+	}
+	for _, r := range []rune{'A', 'Z', 'a', 'z', 0x00AA} {
+		if !unicode.Is(latin.Ranges, r) {
+			t.Errorf("Latin.Is(%q) = false, want true", r)
+		}
+	}
+	if unicode.Is(latin.Ranges, '0') {
+		t.Errorf("Latin.Is('0') = true, want false")
+	}
+}
+
+func TestParseAstralRange(t *testing.T) {
+	set := Parse(sampleScripts)
+	common, ok := set["Common"]
+	if !ok {
+		t.Fatal(`"Common" not found in parsed set`)
+	}
+	for _, r := range []rune{0x0000, 0x0040, 0x1F600, 0x1F64F} {
+		if !unicode.Is(common.Ranges, r) {
+			t.Errorf("Common.Is(%#U) = false, want true", r)
+		}
+	}
+	if unicode.Is(common.Ranges, 0x1F650) {
+		t.Errorf("Common.Is(0x1F650) = true, want false")
+	}
+}
+
+func TestParseIgnoresComments(t *testing.T) {
+	set := Parse(sampleScripts)
+	if len(set) != 2 {
+		t.Fatalf("len(set) = %d, want 2", len(set))
+	}
+}
@@ -14,6 +14,16 @@ usage: unicode [-c] [-d] [-n] [-t]
 	-t: output plain text, not one char per line
 	-U: output full Unicode description
 	-s: sort before output (only useful with -g and multiple regexps)
+	-script=name: restrict to code points in the named script
+	-block=name: restrict to code points in the named block
+	-category=name: restrict to code points in the named general category
+	-case: describe case foldings and case mappings
+	-fold: args are characters; output code points with the same full case fold
+	-version=ver: query the named Unicode version instead of the default
+	-ucd-dir=path: query a UCD snapshot loaded from this directory
+	-utf8: output UTF-8 bytes in hex
+	-utf16: output UTF-16 code units in hex (see -be, -le)
+	-esc: output \uXXXX / \U00XXXXXX escapes (see -surrogates, -ascii)
 
 Default behavior sniffs the arguments to select -c vs. -n.
 
@@ -25,19 +35,64 @@ not have a Unicode 1.0 Name, it will have the semicolon followed
 by the empty string (as a placeholder). This allows for querys
 to single-out Name or 1.0 Name, e.g., '^regexp1;' to fully match
 Name, or ';regexp2' to match just the start of a 1.0 Name.
+
+The -script, -block, and -category flags restrict the candidate
+code points to those in the named Unicode script, block, or general
+category (e.g. -script=Cyrillic, -block=Mathematical_Operators,
+-category=Lu), and may be combined with each other and with -g
+using AND semantics. If a restriction is given with no arguments,
+every code point satisfying it is enumerated (with -g or -fold,
+nothing is reported).
+
+-case prints each code point's case foldings (common, full, simple,
+and Turkic, from CaseFolding.txt) and its full lower-, title-, and
+uppercase mappings (from SpecialCasing.txt), including the
+multi-character expansions that UnicodeData.txt cannot represent.
+
+-fold treats its arguments as a case-folded search key: it computes
+the full case fold of the given characters and reports every code
+point whose own full case fold is equal, so '-fold ß' reports both
+ß (U+00DF) and ẞ (U+1E9E).
+
+unicode embeds several versions of the Unicode Character Database.
+-version selects one by name (e.g. -version=15.1); if -version is
+absent, the $UNICODE_VERSION environment variable is used instead,
+defaulting to the latest embedded version. -ucd-dir=path loads an
+on-disk UCD snapshot instead, for users tracking pre-release drafts;
+it takes precedence over -version and $UNICODE_VERSION.
+
+-utf8 prints each code point's UTF-8 encoding as space-separated hex
+bytes. -utf16 prints its UTF-16 code units, correctly emitting a
+surrogate pair for supplementary code points; -be or -le additionally
+split each unit into two hex bytes in that order, instead of printing
+the 4-digit code unit. -esc prints a \uXXXX escape for a code point
+in the Basic Multilingual Plane or a \U00XXXXXX escape (or, with
+-surrogates, a \u surrogate pair) for a supplementary code point;
+with -ascii, \, ", \n, \r, and \t use their Go/JSON short escapes,
+other ASCII control characters (and DEL) use \u00XX, and the rest
+of printable ASCII passes through unescaped. These
+three replace the usual hex/character output and make unicode a
+quick companion to strconv.Quote and utf8.EncodeRune at the shell.
 */
 package main // import "robpike.io/cmd/unicode"
 
 import (
 	"bytes"
-	_ "embed"
+	"embed"
 	"flag"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"robpike.io/cmd/unicode/internal/ucd"
+	"robpike.io/cmd/unicode/internal/unicodeprop"
 )
 
 var (
@@ -49,46 +104,193 @@ var (
 	doUNIC = flag.Bool("U", false, "describe the characters from the Unicode database, in glorious detail")
 	doGrep = flag.Bool("g", false, "grep for argument string in data")
 	doSort = flag.Bool("s", false, "sort characters before outputting/describing")
+	doCase = flag.Bool("case", false, "describe case foldings and case mappings")
+	doFold = flag.Bool("fold", false, "args are characters; output code points with the same full case fold")
+
+	doUTF8         = flag.Bool("utf8", false, "output UTF-8 bytes in hex")
+	doUTF16        = flag.Bool("utf16", false, "output UTF-16 code units in hex")
+	beFlag         = flag.Bool("be", false, "with -utf16, output big-endian byte pairs instead of code units")
+	leFlag         = flag.Bool("le", false, "with -utf16, output little-endian byte pairs instead of code units")
+	doEsc          = flag.Bool("esc", false, `output \uXXXX / \U00XXXXXX escapes`)
+	surrogatesFlag = flag.Bool("surrogates", false, "with -esc, encode supplementary code points as a \\u surrogate pair")
+	asciiFlag      = flag.Bool("ascii", false, "with -esc, use Go/JSON short escapes and restrict remaining output to ASCII")
+
+	scriptFlag   = flag.String("script", "", "restrict to code points in the named Unicode script")
+	blockFlag    = flag.String("block", "", "restrict to code points in the named Unicode block")
+	categoryFlag = flag.String("category", "", "restrict to code points in the named Unicode general category")
+
+	versionFlag = flag.String("version", "", "query the named Unicode version (default: latest, or $UNICODE_VERSION)")
+	ucdDirFlag  = flag.String("ucd-dir", "", "query a UCD snapshot loaded from this directory")
 )
 
 var printRange = false
 
 const delim = ";"
 
-// See <https://www.unicode.org/reports/tr44/#Data_Fields> for
-// the broader spec for this file.
+// defaultVersionName names the version used when -version is unset
+// and $UNICODE_VERSION is empty.
+const defaultVersionName = "latest"
+
+// Several versions of the Unicode Character Database are embedded so
+// that -version (or $UNICODE_VERSION) can select among them at run
+// time, mirroring the convention used by x/text's UCD generators.
 //
-//go:generate sh -c "curl http://ftp.unicode.org/Public/UNIDATA/UnicodeData.txt >UnicodeData.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/UnicodeData.txt >ucd/6.3/UnicodeData.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/Scripts.txt >ucd/6.3/Scripts.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/Blocks.txt >ucd/6.3/Blocks.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/extracted/DerivedGeneralCategory.txt >ucd/6.3/DerivedGeneralCategory.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/CaseFolding.txt >ucd/6.3/CaseFolding.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/6.3.0/ucd/SpecialCasing.txt >ucd/6.3/SpecialCasing.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/UnicodeData.txt >ucd/10.0/UnicodeData.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/Scripts.txt >ucd/10.0/Scripts.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/Blocks.txt >ucd/10.0/Blocks.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/extracted/DerivedGeneralCategory.txt >ucd/10.0/DerivedGeneralCategory.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/CaseFolding.txt >ucd/10.0/CaseFolding.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/10.0.0/ucd/SpecialCasing.txt >ucd/10.0/SpecialCasing.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/UnicodeData.txt >ucd/15.1/UnicodeData.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/Scripts.txt >ucd/15.1/Scripts.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/Blocks.txt >ucd/15.1/Blocks.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/extracted/DerivedGeneralCategory.txt >ucd/15.1/DerivedGeneralCategory.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/CaseFolding.txt >ucd/15.1/CaseFolding.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/15.1.0/ucd/SpecialCasing.txt >ucd/15.1/SpecialCasing.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/UnicodeData.txt >ucd/latest/UnicodeData.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/Scripts.txt >ucd/latest/Scripts.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/Blocks.txt >ucd/latest/Blocks.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/extracted/DerivedGeneralCategory.txt >ucd/latest/DerivedGeneralCategory.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/CaseFolding.txt >ucd/latest/CaseFolding.txt"
+//go:generate sh -c "curl https://www.unicode.org/Public/UCD/latest/ucd/SpecialCasing.txt >ucd/latest/SpecialCasing.txt"
 var (
-	//go:embed UnicodeData.txt
-	unicodeDataTxt string
-
-	// unicodeLines is a slice of strings of lines from UnicodeData.txt.
-	// Each line contains 15 fields separated by delim. See
-	// <https://www.unicode.org/reports/tr44/#UnicodeData.txt> for
-	// field definitions.
-	unicodeLines = splitLines(unicodeDataTxt)
+	//go:embed ucd/6.3
+	ucd63FS embed.FS
+	//go:embed ucd/10.0
+	ucd100FS embed.FS
+	//go:embed ucd/15.1
+	ucd151FS embed.FS
+	//go:embed ucd/latest
+	ucdLatestFS embed.FS
 )
 
+// versions holds every embedded UCD version, keyed by version name.
+var versions = map[string]*ucd.Version{
+	"6.3":    mustLoadEmbedded("6.3", ucd63FS, "ucd/6.3"),
+	"10.0":   mustLoadEmbedded("10.0", ucd100FS, "ucd/10.0"),
+	"15.1":   mustLoadEmbedded("15.1", ucd151FS, "ucd/15.1"),
+	"latest": mustLoadEmbedded("latest", ucdLatestFS, "ucd/latest"),
+}
+
+// mustLoadEmbedded builds the named *ucd.Version from the six UCD
+// files in dir within fsys.
+func mustLoadEmbedded(name string, fsys embed.FS, dir string) *ucd.Version {
+	read := func(file string) string {
+		data, err := fsys.ReadFile(path.Join(dir, file))
+		if err != nil {
+			fatalf("%s", err)
+		}
+		return string(data)
+	}
+	v, err := ucd.New(name, ucdFiles(read))
+	if err != nil {
+		fatalf("%s", err)
+	}
+	return v
+}
+
+// loadVersionDir builds a *ucd.Version, named after dir's base name,
+// from the six UCD files found on disk in dir.
+func loadVersionDir(dir string) *ucd.Version {
+	read := func(file string) string {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			fatalf("%s", err)
+		}
+		return string(data)
+	}
+	v, err := ucd.New(filepath.Base(dir), ucdFiles(read))
+	if err != nil {
+		fatalf("%s", err)
+	}
+	return v
+}
+
+// ucdFiles reads the six files that make up a ucd.Files bundle using read.
+func ucdFiles(read func(file string) string) ucd.Files {
+	return ucd.Files{
+		UnicodeData:            read("UnicodeData.txt"),
+		Scripts:                read("Scripts.txt"),
+		Blocks:                 read("Blocks.txt"),
+		DerivedGeneralCategory: read("DerivedGeneralCategory.txt"),
+		CaseFolding:            read("CaseFolding.txt"),
+		SpecialCasing:          read("SpecialCasing.txt"),
+	}
+}
+
+// resolveVersion picks the *ucd.Version to query: -ucd-dir if given,
+// else -version, else $UNICODE_VERSION, else defaultVersionName.
+func resolveVersion() *ucd.Version {
+	if *ucdDirFlag != "" {
+		return loadVersionDir(*ucdDirFlag)
+	}
+	name := *versionFlag
+	if name == "" {
+		name = os.Getenv("UNICODE_VERSION")
+	}
+	if name == "" {
+		name = defaultVersionName
+	}
+	v, ok := versions[name]
+	if !ok {
+		fatalf("unknown Unicode version %q", name)
+	}
+	return v
+}
+
 func main() {
 	flag.Usage = usage
 	flag.Parse()
 	mode()
+	v := resolveVersion()
+	filter := propFilter(v)
 	var codes []rune
 	switch {
 	case *doGrep:
-		codes = argsAreRegexps()
+		codes = argsAreRegexps(v)
 		codes = dedupe(codes)
 		if *doSort {
 			slices.Sort(codes)
 		}
+	case *doFold:
+		codes = argsAreFold(v)
+		if *doSort {
+			slices.Sort(codes)
+		}
+	case filter != nil && len(flag.Args()) == 0:
+		codes = allRunes(v, filter)
 	case *doChar:
 		codes = argsAreNumbers()
 	case *doNum:
 		codes = argsAreChars()
 	}
+	if filter != nil {
+		codes = filterRunes(codes, filter)
+	}
+	if *doCase {
+		caseInfo(v, codes)
+		return
+	}
 	if *doUnic || *doUNIC || *doDesc {
-		desc(codes)
+		desc(v, codes)
+		return
+	}
+	if *doUTF8 {
+		printUTF8(codes)
+		return
+	}
+	if *doUTF16 {
+		printUTF16(codes)
+		return
+	}
+	if *doEsc {
+		printEsc(codes)
 		return
 	}
 	if *doText {
@@ -134,6 +336,16 @@ const usageText = `usage: unicode [-c] [-d] [-n] [-t]
 -t: output plain text, not one char per line
 -U: output full Unicode description
 -s: sort before output (only useful with -g and multiple regexps)
+-script=name: restrict to code points in the named script
+-block=name: restrict to code points in the named block
+-category=name: restrict to code points in the named general category
+-case: describe case foldings and case mappings
+-fold: args are characters; output code points with the same full case fold
+-version=ver: query the named Unicode version instead of the default
+-ucd-dir=path: query a UCD snapshot loaded from this directory
+-utf8: output UTF-8 bytes in hex
+-utf16: output UTF-16 code units in hex (see -be, -le)
+-esc: output \uXXXX / \U00XXXXXX escapes (see -surrogates, -ascii)
 
 Default behavior sniffs the arguments to select -c vs. -n.
 
@@ -145,6 +357,44 @@ not have a Unicode 1.0 Name, it will have the semicolon followed
 by the empty string (as a placeholder). This allows for querys
 to single-out Name or 1.0 Name, e.g., '^regexp1;' to fully match
 Name, or ';regexp2' to match just the start of a 1.0 Name.
+
+The -script, -block, and -category flags restrict the candidate
+code points to those in the named Unicode script, block, or general
+category (e.g. -script=Cyrillic, -block=Mathematical_Operators,
+-category=Lu), and may be combined with each other and with -g
+using AND semantics. If a restriction is given with no arguments,
+every code point satisfying it is enumerated (with -g or -fold,
+nothing is reported).
+
+-case prints each code point's case foldings (common, full, simple,
+and Turkic, from CaseFolding.txt) and its full lower-, title-, and
+uppercase mappings (from SpecialCasing.txt), including the
+multi-character expansions that UnicodeData.txt cannot represent.
+
+-fold treats its arguments as a case-folded search key: it computes
+the full case fold of the given characters and reports every code
+point whose own full case fold is equal, so '-fold ß' reports both
+ß (U+00DF) and ẞ (U+1E9E).
+
+unicode embeds several versions of the Unicode Character Database.
+-version selects one by name (e.g. -version=15.1); if -version is
+absent, the $UNICODE_VERSION environment variable is used instead,
+defaulting to the latest embedded version. -ucd-dir=path loads an
+on-disk UCD snapshot instead, for users tracking pre-release drafts;
+it takes precedence over -version and $UNICODE_VERSION.
+
+-utf8 prints each code point's UTF-8 encoding as space-separated hex
+bytes. -utf16 prints its UTF-16 code units, correctly emitting a
+surrogate pair for supplementary code points; -be or -le additionally
+split each unit into two hex bytes in that order, instead of printing
+the 4-digit code unit. -esc prints a \uXXXX escape for a code point
+in the Basic Multilingual Plane or a \U00XXXXXX escape (or, with
+-surrogates, a \u surrogate pair) for a supplementary code point;
+with -ascii, \, ", \n, \r, and \t use their Go/JSON short escapes,
+other ASCII control characters (and DEL) use \u00XX, and the rest
+of printable ASCII passes through unescaped. These
+three replace the usual hex/character output and make unicode a
+quick companion to strconv.Quote and utf8.EncodeRune at the shell.
 `
 
 func usage() {
@@ -154,13 +404,26 @@ func usage() {
 // Mode determines whether we have numeric or character input.
 // If there are no flags, we sniff the first argument.
 func mode() {
-	if len(flag.Args()) == 0 {
+	if len(flag.Args()) == 0 && !hasPropFilter() {
 		usage()
 	}
-	// If grepping names, we need an output format defined; default is numeric.
-	if *doGrep && !(*doNum || *doChar || *doDesc || *doUnic || *doUNIC) {
+	// If grepping names or folding, we need an output format defined;
+	// default is numeric.
+	if (*doGrep || *doFold) && !(*doNum || *doChar || *doDesc || *doUnic || *doUNIC || *doCase || *doUTF8 || *doUTF16 || *doEsc) {
 		*doNum = true
 	}
+	if *doGrep || *doFold {
+		return
+	}
+	if len(flag.Args()) == 0 && hasPropFilter() {
+		// No characters or regexps given; -script/-block/-category
+		// alone enumerate every matching code point, defaulting to
+		// numeric output like -g does.
+		if !(*doNum || *doChar || *doDesc || *doUnic || *doUNIC || *doCase || *doUTF8 || *doUTF16 || *doEsc) {
+			*doNum = true
+		}
+		return
+	}
 	if *doNum || *doChar {
 		return
 	}
@@ -232,62 +495,48 @@ func argsAreNumbers() []rune {
 // one semicolon, between Name and Unicode 1.0 Name. Even if a
 // search-string doesn't have a 1.0 Name, it will have the semicolon
 // followed by the empty string, '{Name};'.
-func argsAreRegexps() []rune {
+//
+// When a regexp is a literal string (no metacharacters) of at least
+// three bytes, v.DB's trigram index prefilters the candidate entries, and the literal is
+// confirmed directly against each candidate's Search string, bypassing
+// the regexp engine entirely; otherwise every entry is scanned as before.
+func argsAreRegexps(v *ucd.Version) []rune {
 	var codes []rune
 	for _, a := range flag.Args() {
 		re, err := regexp.Compile(a)
 		if err != nil {
 			fatalf("%s", err)
 		}
-		for _, line := range unicodeLines {
-			fields := strings.Split(strings.ToLower(line), ";")
-			line = fields[1] + ";" + fields[10]
-			if re.MatchString(line) {
-				codes = append(codes, parseRune(fields[0]))
+		if lit, complete := re.LiteralPrefix(); complete {
+			if idx := v.DB.CandidateIndices(lit); idx != nil {
+				entries := v.DB.Entries()
+				for _, i := range idx {
+					if e := entries[i]; strings.Contains(e.Search, lit) {
+						codes = append(codes, e.Rune)
+					}
+				}
+				continue
 			}
 		}
-	}
-	return codes
-}
-
-func splitLines(text string) []string {
-	lines := strings.Split(text, "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		if len(lines[i]) == 0 {
-			lines = slices.Delete(lines, i, i+1)
-			continue
-		}
-		if strings.Index(lines[i], delim) < 0 {
-			fatalf("malformed database: line %d", i+1)
+		for _, e := range v.DB.Entries() {
+			if re.MatchString(e.Search) {
+				codes = append(codes, e.Rune)
+			}
 		}
 	}
-	return lines
-}
-
-// runeOfLine returns the parsed rune and the index of its
-// trailing delimiter.
-func runeOfLine(line string) (r rune, i int) {
-	i = strings.Index(line, delim)
-	return parseRune(line[0:i]), i
+	return codes
 }
 
-func desc(codes []rune) {
-	runeData := make(map[rune]string)
-	for _, l := range unicodeLines {
-		r, i := runeOfLine(l)
-		runeData[r] = l[i+1:]
-	}
-	if *doUNIC {
-		for _, r := range codes {
-			fmt.Printf("%#U %s", r, dumpUnicode(runeData[r]))
-		}
-	} else if *doUnic {
-		for _, r := range codes {
-			fmt.Printf("%#U %s\n", r, runeData[r])
-		}
-	} else {
-		for _, r := range codes {
-			fields := strings.Split(strings.ToLower(runeData[r]), delim)
+func desc(v *ucd.Version, codes []rune) {
+	for _, r := range codes {
+		e, _ := v.DB.Lookup(r)
+		switch {
+		case *doUNIC:
+			fmt.Printf("%#U %s", r, dumpUnicode(e.Rest))
+		case *doUnic:
+			fmt.Printf("%#U %s\n", r, e.Rest)
+		default:
+			fields := strings.Split(strings.ToLower(e.Rest), delim)
 			desc := fields[0]
 			if len(desc) >= 9 && fields[9] != "" {
 				desc += "; " + fields[9]
@@ -353,3 +602,235 @@ func dumpUnicode(line string) []byte {
 	}
 	return b.Bytes()
 }
+
+// hasPropFilter reports whether -script, -block, or -category was given.
+func hasPropFilter() bool {
+	return *scriptFlag != "" || *blockFlag != "" || *categoryFlag != ""
+}
+
+// propFilter returns a predicate reporting whether a rune satisfies
+// every -script, -block, and -category restriction given on the
+// command line (AND semantics), or nil if none were given.
+func propFilter(v *ucd.Version) func(r rune) bool {
+	var tables []*unicode.RangeTable
+	for _, f := range []struct {
+		flag string
+		set  unicodeprop.Set
+		name string
+	}{
+		{"-script", v.Scripts, *scriptFlag},
+		{"-block", v.Blocks, *blockFlag},
+		{"-category", v.Categories, *categoryFlag},
+	} {
+		if f.name == "" {
+			continue
+		}
+		t, ok := f.set[f.name]
+		if !ok {
+			fatalf("unknown value %q for %s", f.name, f.flag)
+		}
+		tables = append(tables, t.Ranges)
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	return func(r rune) bool {
+		for _, t := range tables {
+			if !unicode.Is(t, r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// allRunes returns every code point v.DB can look up, including those
+// inside First/Last ranges, for which filter reports true.
+func allRunes(v *ucd.Version, filter func(r rune) bool) []rune {
+	var codes []rune
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if !filter(r) {
+			continue
+		}
+		if _, ok := v.DB.Lookup(r); ok {
+			codes = append(codes, r)
+		}
+	}
+	return codes
+}
+
+// filterRunes returns the subset of codes for which filter reports true.
+func filterRunes(codes []rune, filter func(r rune) bool) []rune {
+	kept := codes[:0]
+	for _, r := range codes {
+		if filter(r) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// argsAreFold treats the characters in the arguments as a single
+// case-folded search key and returns every assigned code point whose
+// own full case fold equals that key, so e.g. searching U+00DF (ß)
+// also finds U+1E9E (ẞ), since both fold to "ss".
+func argsAreFold(v *ucd.Version) []rune {
+	key := fullFold(v, strings.Join(flag.Args(), ""))
+	var codes []rune
+	for _, e := range v.DB.Entries() {
+		if fullFold(v, string(e.Rune)) == key {
+			codes = append(codes, e.Rune)
+		}
+	}
+	return codes
+}
+
+// fullFold returns the full case fold of s: the concatenation of the
+// full case fold of each of its runes.
+func fullFold(v *ucd.Version, s string) string {
+	b := new(strings.Builder)
+	for _, r := range s {
+		b.WriteString(string(v.CaseFolding.Fold(r)))
+	}
+	return b.String()
+}
+
+// caseInfo prints, for each code point in codes, its simple and full
+// case foldings (from CaseFolding.txt) and its full lower-, title-,
+// and uppercase mappings (from SpecialCasing.txt), labeling each
+// mapping present.
+func caseInfo(v *ucd.Version, codes []rune) {
+	for _, r := range codes {
+		fmt.Printf("%#U\n", r)
+		if m, ok := v.CaseFolding.C[r]; ok {
+			fmt.Printf("\tcommon casefold: %s\n", string(m))
+		}
+		if m, ok := v.CaseFolding.F[r]; ok {
+			fmt.Printf("\tfull casefold: %s\n", string(m))
+		}
+		if m, ok := v.CaseFolding.S[r]; ok {
+			fmt.Printf("\tsimple casefold: %s\n", string(m))
+		}
+		if m, ok := v.CaseFolding.T[r]; ok {
+			fmt.Printf("\tTurkic casefold: %s\n", string(m))
+		}
+		if sc, ok := v.SpecialCasing[r]; ok {
+			if len(sc.Lower) > 0 {
+				fmt.Printf("\tfull lowercase: %s\n", string(sc.Lower))
+			}
+			if len(sc.Title) > 0 {
+				fmt.Printf("\tfull titlecase: %s\n", string(sc.Title))
+			}
+			if len(sc.Upper) > 0 {
+				fmt.Printf("\tfull uppercase: %s\n", string(sc.Upper))
+			}
+		}
+	}
+}
+
+// printUTF8 prints, for each code point in codes, its UTF-8 encoding
+// as space-separated hex bytes, one line per code point.
+func printUTF8(codes []rune) {
+	buf := make([]byte, utf8.UTFMax)
+	for _, r := range codes {
+		n := utf8.EncodeRune(buf, r)
+		fmt.Println(hexBytes(buf[:n]))
+	}
+}
+
+// hexBytes formats b as space-separated two-digit hex bytes.
+func hexBytes(b []byte) string {
+	parts := make([]string, len(b))
+	for i, c := range b {
+		parts[i] = fmt.Sprintf("%.2x", c)
+	}
+	return strings.Join(parts, " ")
+}
+
+// utf16Units returns the UTF-16 code units encoding r, correctly
+// emitting a surrogate pair for supplementary code points, using the
+// standard 0xD800+((r-0x10000)>>10), 0xDC00+((r-0x10000)&0x3FF) formula.
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+	r -= 0x10000
+	return []uint16{
+		uint16(0xD800 + (r >> 10)),
+		uint16(0xDC00 + (r & 0x3FF)),
+	}
+}
+
+// printUTF16 prints, for each code point in codes, its UTF-16 code
+// units. With neither -be nor -le, each unit is printed as a 4-digit
+// hex value; with -be or -le, each unit is instead printed as its two
+// hex bytes in that order.
+func printUTF16(codes []rune) {
+	for _, r := range codes {
+		var parts []string
+		for _, u := range utf16Units(r) {
+			switch {
+			case *leFlag:
+				parts = append(parts, fmt.Sprintf("%.2x", u&0xff), fmt.Sprintf("%.2x", u>>8))
+			case *beFlag:
+				parts = append(parts, fmt.Sprintf("%.2x", u>>8), fmt.Sprintf("%.2x", u&0xff))
+			default:
+				parts = append(parts, fmt.Sprintf("%.4x", u))
+			}
+		}
+		fmt.Println(strings.Join(parts, " "))
+	}
+}
+
+// escRune returns r's -esc escape: \uXXXX for code points in the
+// Basic Multilingual Plane, \U00XXXXXX for supplementary code points,
+// or (with -surrogates) a \u surrogate pair instead. With -ascii,
+// backslash, double quote, and the common control characters use
+// their Go/JSON short escapes and the rest of printable ASCII passes
+// through unescaped; everything else still falls back to \u/\U.
+func escRune(r rune) string {
+	if *asciiFlag {
+		switch r {
+		case '\\':
+			return `\\`
+		case '"':
+			return `\"`
+		case '\n':
+			return `\n`
+		case '\r':
+			return `\r`
+		case '\t':
+			return `\t`
+		}
+		if r == 0x7f || r < 0x20 {
+			return fmt.Sprintf(`\u%04x`, r)
+		}
+		if r < 0x80 {
+			return string(r)
+		}
+	}
+	if r > 0xFFFF {
+		if *surrogatesFlag {
+			units := utf16Units(r)
+			return fmt.Sprintf(`\u%04x\u%04x`, units[0], units[1])
+		}
+		return fmt.Sprintf(`\U%08x`, r)
+	}
+	return fmt.Sprintf(`\u%04x`, r)
+}
+
+// printEsc prints the -esc escape for each code point in codes, one
+// per line, or as a single concatenated line if -t is also given.
+func printEsc(codes []rune) {
+	if *doText {
+		b := new(strings.Builder)
+		for _, r := range codes {
+			b.WriteString(escRune(r))
+		}
+		fmt.Println(b.String())
+		return
+	}
+	for _, r := range codes {
+		fmt.Println(escRune(r))
+	}
+}
@@ -40,6 +40,21 @@ func TestCLI(t *testing.T) {
 			*doUnic = false
 			*doUNIC = false
 			*doGrep = false
+			*doSort = false
+			*doCase = false
+			*doFold = false
+			*doUTF8 = false
+			*doUTF16 = false
+			*beFlag = false
+			*leFlag = false
+			*doEsc = false
+			*surrogatesFlag = false
+			*asciiFlag = false
+			*scriptFlag = ""
+			*blockFlag = ""
+			*categoryFlag = ""
+			*versionFlag = ""
+			*ucdDirFlag = ""
 			printRange = false
 
 			// Backup and restore OS